@@ -1,262 +1,340 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"log"
-	"net/http"
 	"os"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/mikeshootzz/SBB-CLI/providers"
+	"github.com/mikeshootzz/SBB-CLI/providers/entur"
+	"github.com/mikeshootzz/SBB-CLI/providers/opendatach"
+	"github.com/mikeshootzz/SBB-CLI/providers/transportclient"
+	"github.com/mikeshootzz/SBB-CLI/render"
+	"github.com/mikeshootzz/SBB-CLI/stations"
+	"github.com/mikeshootzz/SBB-CLI/stats"
 )
 
-// APIResponse represents the JSON response from the transport API.
-type APIResponse struct {
-	Connections []Connection `json:"connections"`
-}
+// defaultProvider is used when neither --provider nor SBB_PROVIDER is set.
+const defaultProvider = "opendatach"
 
-// Connection represents an overall journey.
-type Connection struct {
-	From     Stop      `json:"from"`
-	To       Stop      `json:"to"`
-	Duration string    `json:"duration"` // e.g., "00d00:55:00"
-	Sections []Section `json:"sections"`
+// defaultOutput is used when --output/-o isn't given.
+const defaultOutput = "fancy"
+
+// newProvider constructs the Provider registered under name, routing all of
+// its HTTP access through tc so responses are cached on disk.
+func newProvider(name string, tc *transportclient.Client) (providers.Provider, error) {
+	switch name {
+	case "opendatach":
+		return opendatach.New(tc), nil
+	case "entur":
+		return entur.New(tc), nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q (want one of: opendatach, entur)", name)
+	}
 }
 
-// Section represents one leg (step) of a journey.
-type Section struct {
-	Departure Stop     `json:"departure"`
-	Arrival   Stop     `json:"arrival"`
-	Journey   *Journey `json:"journey"` // may be nil for a walking transfer
+// noCacheFlag strips --no-cache from args if present, reporting whether it
+// was found.
+func noCacheFlag(args []string) (bool, []string) {
+	for i, a := range args {
+		if a == "--no-cache" {
+			return true, append(args[:i], args[i+1:]...)
+		}
+	}
+	return false, args
 }
 
-// Journey holds information about the transportation used in a section.
-type Journey struct {
-	Category string `json:"category"` // e.g., "S" or "IR"
-	Number   string `json:"number"`   // e.g., "14" or "36"
-	Operator string `json:"operator"` // not used in display
-	To       string `json:"to"`       // final destination of this leg
+// noColorFlag strips --no-color from args if present, reporting whether it
+// was found.
+func noColorFlag(args []string) (bool, []string) {
+	for i, a := range args {
+		if a == "--no-color" {
+			return true, append(args[:i], args[i+1:]...)
+		}
+	}
+	return false, args
 }
 
-// Stop holds the details for a departure or arrival.
-type Stop struct {
-	Departure string     `json:"departure"` // ISO8601 time string
-	Arrival   string     `json:"arrival"`   // ISO8601 time string
-	Platform  string     `json:"platform"`  // planned platform
-	Station   Station    `json:"station"`
-	Prognosis *Prognosis `json:"prognosis,omitempty"`
+// stationsFileFlag strips --stations-file <path> from args if present,
+// returning the path (empty if not given).
+func stationsFileFlag(args []string) (string, []string) {
+	for i, a := range args {
+		if a == "--stations-file" {
+			if i+1 < len(args) {
+				path := args[i+1]
+				return path, append(args[:i], args[i+2:]...)
+			}
+			return "", append(args[:i], args[i+1:]...)
+		}
+		if strings.HasPrefix(a, "--stations-file=") {
+			path := strings.TrimPrefix(a, "--stations-file=")
+			return path, append(args[:i], args[i+1:]...)
+		}
+	}
+	return "", args
 }
 
-// Station represents a station or stop.
-type Station struct {
-	Name string `json:"name"`
+// providerName resolves the provider to use from --provider/-p args (args
+// are mutated to strip the flag) or, failing that, SBB_PROVIDER.
+func providerName(args []string) (string, []string) {
+	for i, a := range args {
+		if a == "--provider" || a == "-p" {
+			if i+1 < len(args) {
+				name := args[i+1]
+				return name, append(args[:i], args[i+2:]...)
+			}
+			return defaultProvider, append(args[:i], args[i+1:]...)
+		}
+		if strings.HasPrefix(a, "--provider=") {
+			name := strings.TrimPrefix(a, "--provider=")
+			return name, append(args[:i], args[i+1:]...)
+		}
+	}
+	if name := os.Getenv("SBB_PROVIDER"); name != "" {
+		return name, args
+	}
+	return defaultProvider, args
 }
 
-// Prognosis holds the realtime information (if available) for a stop.
-type Prognosis struct {
-	Platform    string `json:"platform"`
-	Arrival     string `json:"arrival"`
-	Departure   string `json:"departure"`
-	Capacity1st string `json:"capacity1st"`
-	Capacity2nd string `json:"capacity2nd"`
+// outputFormat resolves the --output/-o format from args (args are mutated
+// to strip the flag), defaulting to "fancy".
+func outputFormat(args []string) (string, []string) {
+	for i, a := range args {
+		if a == "--output" || a == "-o" {
+			if i+1 < len(args) {
+				format := args[i+1]
+				return format, append(args[:i], args[i+2:]...)
+			}
+			return defaultOutput, append(args[:i], args[i+1:]...)
+		}
+		if strings.HasPrefix(a, "--output=") {
+			format := strings.TrimPrefix(a, "--output=")
+			return format, append(args[:i], args[i+1:]...)
+		}
+	}
+	return defaultOutput, args
 }
 
 func main() {
-	if len(os.Args) < 3 {
-		fmt.Println("Usage: transport <from> <to>")
-		os.Exit(1)
+	noCache, args := noCacheFlag(os.Args[1:])
+	noColor, args := noColorFlag(args)
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		color.NoColor = true
 	}
-	from := os.Args[1]
-	to := os.Args[2]
 
-	// Header with ASCII art and emojis.
-	fmt.Println(`
-   ____  _     _       ____           _
-  / ___|| |_  (_) ___ |  _ \ ___  ___| |_ ___  _ __
-  \___ \| __| | |/ __|| |_) / _ \/ __| __/ _ \| '__|
-   ___) | |_  | |\__ \|  _ <  __/\__ \ || (_) | |
-  |____/ \__| |_||___/|_| \_\___||___/\__\___/|_|
+	stationsFile, args := stationsFileFlag(args)
+	format, args := outputFormat(args)
+	name, args := providerName(args)
 
-🚆  Welcome to Transport CLI 🚏
-`)
+	// Dispatched after the flag strippers above (not just args[0]=="cache")
+	// so flag placement doesn't matter: `sbb --provider entur cache purge`
+	// and `sbb cache --provider entur purge` both still strip down to
+	// args == ["cache", "purge"].
+	if len(args) > 0 && args[0] == "cache" {
+		runCacheCmd(args[1:])
+		return
+	}
 
-	// Build the API URL.
-	apiURL := fmt.Sprintf("http://transport.opendata.ch/v1/connections?from=%s&to=%s", from, to)
-	resp, err := http.Get(apiURL)
+	r, err := render.New(format)
 	if err != nil {
-		log.Fatalf("Error fetching connections: %v", err)
+		log.Fatalf("Error: %v", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		log.Fatalf("Error: received status code %d", resp.StatusCode)
+	tc := transportclient.New(noCache)
+	provider, err := newProvider(name, tc)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
 	}
 
-	body, err := ioutil.ReadAll(resp.Body)
+	idx, err := stations.NewSeededIndex()
 	if err != nil {
-		log.Fatalf("Error reading response: %v", err)
+		log.Fatalf("Error: %v", err)
+	}
+	if stationsFile != "" {
+		if err := idx.LoadFile(stationsFile); err != nil {
+			log.Fatalf("Error loading --stations-file: %v", err)
+		}
 	}
 
-	var apiResp APIResponse
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		log.Fatalf("Error parsing JSON: %v", err)
+	dbPath, err := stats.DefaultDBPath()
+	var rec *stats.Recorder
+	if err == nil {
+		// Stats recording is a best-effort enhancement: if the db can't be
+		// opened (e.g. a read-only home dir), fall back to not recording
+		// rather than failing the whole CLI over it.
+		rec, _ = stats.NewRecorder(dbPath)
+	}
+	if rec != nil {
+		defer rec.Close()
 	}
 
-	if len(apiResp.Connections) == 0 {
-		fmt.Println("No connections found.")
+	if len(args) > 0 && args[0] == "stats" {
+		runStatsCmd(provider, idx, rec, args[1:])
 		return
 	}
-
-	// Display each connection.
-	for i, conn := range apiResp.Connections {
-		fmt.Printf("\nConnection %d: Overall Duration: %s\n", i+1, formatDurationString(conn.Duration))
-		if len(conn.Sections) > 0 {
-			fmt.Print(displayFancyTimeline(conn.Sections))
-		} else {
-			// Fallback if no sections available.
-			fmt.Printf("%s\n", formatStopFancy(conn.From, true))
-			fmt.Printf("  ──( Walk )──▶\n")
-			fmt.Printf("%s\n", formatStopFancy(conn.To, false))
-		}
-		fmt.Println("--------------------------------")
+	if len(args) > 0 && args[0] == "departures" {
+		runDeparturesCmd(provider, idx, r, args[1:])
+		return
 	}
+	runConnectionsCmd(provider, idx, r, rec, args)
 }
 
-// displayFancyTimeline builds a multi-line, left-to-right timeline for the connection's sections.
-func displayFancyTimeline(sections []Section) string {
-	var builder strings.Builder
-	// Print the first stop using its departure details.
-	builder.WriteString(formatStopFancy(sections[0].Departure, true) + "\n")
-	// For each section, print the journey and the arrival stop.
-	for _, sec := range sections {
-		builder.WriteString("    " + formatJourneyFancy(sec.Journey) + "\n")
-		builder.WriteString(formatStopFancy(sec.Arrival, false) + "\n")
-	}
-	return builder.String()
-}
-
-// formatStopFancy returns a formatted string for a stop including a warning if needed.
-// isDeparture flag indicates whether this is a departure (true) or arrival (false) stop.
-func formatStopFancy(stop Stop, isDeparture bool) string {
-	var t string
-	if isDeparture {
-		t = formatTimeString(stop.Departure)
-	} else {
-		t = formatTimeString(stop.Arrival)
-	}
-	return fmt.Sprintf("[ %s (%s | Plat %s%s) ]",
-		stop.Station.Name,
-		t,
-		stop.Platform,
-		warningSymbol(stop, isDeparture),
-	)
-}
-
-// warningSymbol returns a warning emoji if the stop’s prognosis differs from the schedule.
-// (For example, if the departure/arrival time or platform has changed.)
-func warningSymbol(stop Stop, isDeparture bool) string {
-	if stop.Prognosis != nil {
-		if isDeparture && stop.Prognosis.Departure != "" && stop.Prognosis.Departure != stop.Departure {
-			return " ⚠️"
-		}
-		if !isDeparture && stop.Prognosis.Arrival != "" && stop.Prognosis.Arrival != stop.Arrival {
-			return " ⚠️"
-		}
-		if stop.Prognosis.Platform != "" && stop.Prognosis.Platform != stop.Platform {
-			return " ⚠️"
+// resolveStation turns a possibly partial station name into the canonical
+// name the provider expects, prompting the user to disambiguate when the
+// match isn't clear-cut.
+func resolveStation(idx *stations.Index, provider providers.Provider, raw string) string {
+	best, alternatives, err := stations.ResolveStation(idx, provider, raw)
+	if err != nil {
+		// Fall back to whatever the user typed; the provider will surface
+		// its own "not found" error if it's truly invalid.
+		return raw
+	}
+	if len(alternatives) > 1 && stations.IsAmbiguous(idx, raw) {
+		picked, err := stations.PickStation(os.Stdin, alternatives)
+		if err != nil {
+			return best.Name
 		}
+		return picked.Name
 	}
-	return ""
+	return best.Name
 }
 
-// formatJourneyFancy returns a formatted string for a journey segment, omitting any internal id.
-func formatJourneyFancy(journey *Journey) string {
-	if journey == nil {
-		return "──( Walk )──▶"
+// runCacheCmd implements `sbb cache purge`.
+func runCacheCmd(args []string) {
+	if len(args) == 0 || args[0] != "purge" {
+		fmt.Println("Usage: sbb cache purge")
+		os.Exit(1)
 	}
-	// Display only the category and line number (e.g., "S 14")
-	return fmt.Sprintf("──( %s %s )──▶", journey.Category, journey.Number)
-}
 
-// formatTimeString converts an ISO8601 time string to a "15:04" format.
-func formatTimeString(t string) string {
-	if t == "" {
-		return ""
-	}
-	parsed, err := time.Parse(time.RFC3339, t)
+	dir, err := transportclient.DefaultCacheDir()
 	if err != nil {
-		// Try an alternative layout if the timezone is formatted as +0100 (without colon).
-		parsed, err = time.Parse("2006-01-02T15:04:05-0700", t)
-		if err != nil {
-			return t // return the original if parsing fails
-		}
+		log.Fatalf("Error: %v", err)
 	}
-	return parsed.Format("15:04")
+	if err := transportclient.Purge(dir); err != nil {
+		log.Fatalf("Error purging cache: %v", err)
+	}
+	fmt.Println("Cache purged.")
 }
 
-// formatDurationString converts a duration like "00d00:55:00" into a human-friendly string.
-func formatDurationString(dur string) string {
-	// Expected format: "00d00:55:00" => days 'd' then HH:MM:SS.
-	parts := strings.SplitN(dur, "d", 2)
-	if len(parts) != 2 {
-		return dur
+// runConnectionsCmd implements the default `sbb <from> <to>` routing mode.
+func runConnectionsCmd(provider providers.Provider, idx *stations.Index, r render.Renderer, rec *stats.Recorder, args []string) {
+	if len(args) < 2 {
+		fmt.Println("Usage: sbb [--provider opendatach|entur] [--output fancy|table|json|ndjson|csv] <from> <to>")
+		os.Exit(1)
 	}
-	daysStr := parts[0]
-	timePart := parts[1]
-	days, err := strconv.Atoi(daysStr)
+	from := resolveStation(idx, provider, args[0])
+	to := resolveStation(idx, provider, args[1])
+
+	conns, err := provider.Connections(from, to, time.Time{}, providers.QueryOptions{})
 	if err != nil {
-		return dur
+		log.Fatalf("Error fetching connections: %v", err)
 	}
-	tparts := strings.Split(timePart, ":")
-	if len(tparts) != 3 {
-		return dur
+
+	if rec != nil {
+		for _, conn := range conns {
+			if err := rec.Observe(conn); err != nil {
+				log.Printf("warning: failed to record stats observation: %v", err)
+			}
+		}
 	}
-	hours, err := strconv.Atoi(tparts[0])
-	if err != nil {
-		return dur
+
+	if err := r.Connections(os.Stdout, conns); err != nil {
+		log.Fatalf("Error rendering connections: %v", err)
+	}
+}
+
+// runStatsCmd implements `sbb stats <line> <from> <to>`. from/to accept the
+// same fuzzy fragments as `sbb <from> <to>` and `sbb departures`, resolved
+// through the same idx/provider so they key against what Observe recorded.
+func runStatsCmd(provider providers.Provider, idx *stations.Index, rec *stats.Recorder, args []string) {
+	if rec == nil {
+		log.Fatalf("Error: stats database unavailable")
 	}
-	minutes, err := strconv.Atoi(tparts[1])
+	if len(args) < 3 {
+		fmt.Println("Usage: sbb stats <line> <from> <to>")
+		os.Exit(1)
+	}
+	line := args[0]
+	from := resolveStationKey(idx, provider, args[1])
+	to := resolveStationKey(idx, provider, args[2])
+
+	report, err := stats.Query(rec, line, from, to)
 	if err != nil {
-		return dur
+		log.Fatalf("Error: %v", err)
 	}
-	seconds, err := strconv.Atoi(tparts[2])
+
+	fmt.Printf("%s %s→%s: scheduled %.0fmin, observed %.1fmin ±%.1fmin (n=%d)\n",
+		report.Line, report.From, report.To,
+		report.ScheduledMinutes, report.ObservedMinutes, report.RMSEMinutes, report.N,
+	)
+}
+
+// resolveStationKey resolves a possibly partial station name the same way
+// resolveStation does, but returns the stats.StationKey (ID, falling back
+// to name) observations are actually recorded under, rather than the
+// display name.
+func resolveStationKey(idx *stations.Index, provider providers.Provider, raw string) string {
+	best, _, err := stations.ResolveStation(idx, provider, raw)
 	if err != nil {
-		return dur
-	}
-	var partsOut []string
-	if days > 0 {
-		if days == 1 {
-			partsOut = append(partsOut, fmt.Sprintf("%d day", days))
-		} else {
-			partsOut = append(partsOut, fmt.Sprintf("%d days", days))
-		}
+		return raw
 	}
-	if hours > 0 {
-		if hours == 1 {
-			partsOut = append(partsOut, fmt.Sprintf("%d hour", hours))
-		} else {
-			partsOut = append(partsOut, fmt.Sprintf("%d hours", hours))
+	return stats.StationKey(best)
+}
+
+// runDeparturesCmd implements `sbb departures <station> [--limit N] [--when HH:MM]`.
+func runDeparturesCmd(provider providers.Provider, idx *stations.Index, r render.Renderer, args []string) {
+	limit := 10
+	var when time.Time
+	var station string
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--limit":
+			if i+1 >= len(args) {
+				log.Fatalf("Error: --limit requires a value")
+			}
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil {
+				log.Fatalf("Error: invalid --limit value %q", args[i])
+			}
+			limit = n
+		case "--when":
+			if i+1 >= len(args) {
+				log.Fatalf("Error: --when requires a value")
+			}
+			i++
+			t, err := time.Parse("15:04", args[i])
+			if err != nil {
+				log.Fatalf("Error: invalid --when value %q (want HH:MM)", args[i])
+			}
+			now := time.Now()
+			when = time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		default:
+			if station != "" {
+				log.Fatalf("Error: unexpected argument %q", args[i])
+			}
+			station = args[i]
 		}
 	}
-	if minutes > 0 {
-		if minutes == 1 {
-			partsOut = append(partsOut, fmt.Sprintf("%d minute", minutes))
-		} else {
-			partsOut = append(partsOut, fmt.Sprintf("%d minutes", minutes))
-		}
+
+	if station == "" {
+		fmt.Println("Usage: sbb departures <station> [--limit N] [--when HH:MM]")
+		os.Exit(1)
 	}
-	// Only show seconds if no other unit is significant.
-	if seconds > 0 && days == 0 && hours == 0 && minutes == 0 {
-		if seconds == 1 {
-			partsOut = append(partsOut, fmt.Sprintf("%d second", seconds))
-		} else {
-			partsOut = append(partsOut, fmt.Sprintf("%d seconds", seconds))
-		}
+	station = resolveStation(idx, provider, station)
+
+	departures, err := provider.Stationboard(station, limit, when)
+	if err != nil {
+		log.Fatalf("Error fetching stationboard: %v", err)
 	}
-	if len(partsOut) == 0 {
-		return "0 minutes"
+
+	if err := r.Departures(os.Stdout, station, departures); err != nil {
+		log.Fatalf("Error rendering departures: %v", err)
 	}
-	return strings.Join(partsOut, " ")
 }
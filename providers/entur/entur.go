@@ -0,0 +1,376 @@
+// Package entur implements providers.Provider against the Entur Journey
+// Planner GraphQL API, which covers public transport across Norway.
+package entur
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mikeshootzz/SBB-CLI/providers"
+	"github.com/mikeshootzz/SBB-CLI/providers/transportclient"
+)
+
+// graphQLURL is the Entur Journey Planner v2 GraphQL endpoint.
+const graphQLURL = "https://api.entur.io/journey-planner/v2/graphql"
+
+// clientName identifies this application to Entur, as required by their API
+// terms of use (see https://developer.entur.org/pages-intro-authentication).
+const clientName = "mikeshootzz-sbb-cli"
+
+// Cache TTLs per endpoint, mirroring opendatach: geocoder lookups are
+// essentially static, trip and stop place queries are only worth caching
+// long enough to absorb repeat runs.
+const (
+	stationsTTL     = 7 * 24 * time.Hour
+	tripTTL         = 60 * time.Second
+	stationboardTTL = 60 * time.Second
+)
+
+// Client talks to the Entur Journey Planner GraphQL API on behalf of the CLI.
+type Client struct {
+	TC *transportclient.Client
+}
+
+// New returns a Client ready to query Entur, using tc for all HTTP access
+// so responses are cached on disk.
+func New(tc *transportclient.Client) *Client {
+	return &Client{TC: tc}
+}
+
+// graphQLRequest is the envelope Entur expects for a GraphQL POST.
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+const tripQuery = `
+query Trip($from: Location!, $to: Location!, $numTripPatterns: Int!, $dateTime: DateTime!) {
+  trip(from: $from, to: $to, numTripPatterns: $numTripPatterns, dateTime: $dateTime) {
+    tripPatterns {
+      duration
+      legs {
+        mode
+        realtime
+        line {
+          publicCode
+        }
+        fromEstimatedCall {
+          quay {
+            name
+          }
+          aimedDepartureTime
+          expectedDepartureTime
+          cancellation
+        }
+        toEstimatedCall {
+          quay {
+            name
+          }
+          aimedArrivalTime
+          expectedArrivalTime
+          cancellation
+        }
+      }
+    }
+  }
+}`
+
+// tripResponse mirrors the shape of the trip query's "data" field.
+type tripResponse struct {
+	Data struct {
+		Trip struct {
+			TripPatterns []struct {
+				Duration int `json:"duration"`
+				Legs     []struct {
+					Mode     string `json:"mode"`
+					Realtime bool   `json:"realtime"`
+					Line     struct {
+						PublicCode string `json:"publicCode"`
+					} `json:"line"`
+					FromEstimatedCall estimatedCall `json:"fromEstimatedCall"`
+					ToEstimatedCall   estimatedCall `json:"toEstimatedCall"`
+				} `json:"legs"`
+			} `json:"tripPatterns"`
+		} `json:"trip"`
+	} `json:"data"`
+}
+
+// estimatedCall is the common shape of fromEstimatedCall/toEstimatedCall.
+type estimatedCall struct {
+	Quay struct {
+		Name string `json:"name"`
+	} `json:"quay"`
+	AimedDepartureTime    string `json:"aimedDepartureTime"`
+	ExpectedDepartureTime string `json:"expectedDepartureTime"`
+	AimedArrivalTime      string `json:"aimedArrivalTime"`
+	ExpectedArrivalTime   string `json:"expectedArrivalTime"`
+	Cancellation          bool   `json:"cancellation"`
+}
+
+// Connections implements providers.Provider.
+func (c *Client) Connections(from, to string, when time.Time, opts providers.QueryOptions) ([]providers.Connection, error) {
+	numTripPatterns := opts.Limit
+	if numTripPatterns <= 0 {
+		numTripPatterns = 5
+	}
+	if when.IsZero() {
+		when = time.Now()
+	}
+
+	reqBody := graphQLRequest{
+		Query: tripQuery,
+		Variables: map[string]interface{}{
+			"from":            map[string]interface{}{"name": from},
+			"to":              map[string]interface{}{"name": to},
+			"numTripPatterns": numTripPatterns,
+			"dateTime":        when.Format(time.RFC3339),
+		},
+	}
+
+	var tr tripResponse
+	if err := c.query(reqBody, tripTTL, &tr); err != nil {
+		return nil, err
+	}
+
+	var conns []providers.Connection
+	for _, tp := range tr.Data.Trip.TripPatterns {
+		conns = append(conns, toConnection(tp.Duration, tp.Legs))
+	}
+	return conns, nil
+}
+
+// toConnection maps an Entur tripPattern onto the shared Connection model so
+// the CLI renderer works unchanged regardless of provider.
+func toConnection(duration int, legs []struct {
+	Mode     string `json:"mode"`
+	Realtime bool   `json:"realtime"`
+	Line     struct {
+		PublicCode string `json:"publicCode"`
+	} `json:"line"`
+	FromEstimatedCall estimatedCall `json:"fromEstimatedCall"`
+	ToEstimatedCall   estimatedCall `json:"toEstimatedCall"`
+}) providers.Connection {
+	sections := make([]providers.Section, 0, len(legs))
+	for _, leg := range legs {
+		sections = append(sections, providers.Section{
+			Departure: stopFromCall(leg.FromEstimatedCall, true, leg.Realtime),
+			Arrival:   stopFromCall(leg.ToEstimatedCall, false, leg.Realtime),
+			Journey: &providers.Journey{
+				Category: leg.Mode,
+				Number:   leg.Line.PublicCode,
+			},
+		})
+	}
+
+	conn := providers.Connection{
+		Duration: fmt.Sprintf("00d%02d:%02d:00", duration/3600, (duration%3600)/60),
+		Sections: sections,
+	}
+	if len(sections) > 0 {
+		conn.From = sections[0].Departure
+		conn.To = sections[len(sections)-1].Arrival
+	}
+	return conn
+}
+
+// stopFromCall maps an Entur estimatedCall onto providers.Stop, carrying the
+// realtime estimate (or cancellation) over as a Prognosis whenever the leg is
+// realtime, even when the expected time equals the aimed time.
+func stopFromCall(call estimatedCall, isDeparture bool, realtime bool) providers.Stop {
+	stop := providers.Stop{
+		Station: providers.Station{Name: call.Quay.Name},
+	}
+	if isDeparture {
+		stop.Departure = call.AimedDepartureTime
+		if realtime && (call.ExpectedDepartureTime != "" || call.Cancellation) {
+			stop.Prognosis = &providers.Prognosis{Departure: call.ExpectedDepartureTime, Cancelled: call.Cancellation}
+		}
+	} else {
+		stop.Arrival = call.AimedArrivalTime
+		if realtime && (call.ExpectedArrivalTime != "" || call.Cancellation) {
+			stop.Prognosis = &providers.Prognosis{Arrival: call.ExpectedArrivalTime, Cancelled: call.Cancellation}
+		}
+	}
+	return stop
+}
+
+// searchResponse mirrors a features-based station search against Entur's
+// Geocoder, which backs station name autocompletion.
+type searchResponse struct {
+	Features []struct {
+		Properties struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"properties"`
+	} `json:"features"`
+}
+
+// SearchStations implements providers.Provider.
+func (c *Client) SearchStations(query string) ([]providers.Station, error) {
+	v := url.Values{}
+	v.Set("text", query)
+	v.Set("layers", "venue")
+
+	geocoderURL := fmt.Sprintf("https://api.entur.io/geocoder/v1/autocomplete?%s", v.Encode())
+	body, err := c.TC.Get(geocoderURL, stationsTTL, map[string]string{"ET-Client-Name": clientName})
+	if err != nil {
+		return nil, fmt.Errorf("fetching stations: %w", err)
+	}
+
+	var sr searchResponse
+	if err := json.Unmarshal(body, &sr); err != nil {
+		return nil, fmt.Errorf("parsing stations response: %w", err)
+	}
+
+	stations := make([]providers.Station, 0, len(sr.Features))
+	for _, f := range sr.Features {
+		stations = append(stations, providers.Station{Name: f.Properties.Name, ID: f.Properties.ID})
+	}
+	return stations, nil
+}
+
+const stopPlaceQuery = `
+query StopPlace($id: String!, $numDepartures: Int!, $startTime: DateTime!) {
+  stopPlace(id: $id) {
+    estimatedCalls(numberOfDepartures: $numDepartures, startTime: $startTime) {
+      realtime
+      aimedDepartureTime
+      expectedDepartureTime
+      cancellation
+      quay {
+        publicCode
+      }
+      destinationDisplay {
+        frontText
+      }
+      serviceJourney {
+        line {
+          publicCode
+        }
+        transportMode
+      }
+    }
+  }
+}`
+
+// stopPlaceResponse mirrors the shape of the stopPlace query's "data" field.
+type stopPlaceResponse struct {
+	Data struct {
+		StopPlace struct {
+			EstimatedCalls []struct {
+				Realtime              bool   `json:"realtime"`
+				AimedDepartureTime    string `json:"aimedDepartureTime"`
+				ExpectedDepartureTime string `json:"expectedDepartureTime"`
+				Cancellation          bool   `json:"cancellation"`
+				Quay                  struct {
+					PublicCode string `json:"publicCode"`
+				} `json:"quay"`
+				DestinationDisplay struct {
+					FrontText string `json:"frontText"`
+				} `json:"destinationDisplay"`
+				ServiceJourney struct {
+					Line struct {
+						PublicCode string `json:"publicCode"`
+					} `json:"line"`
+					TransportMode string `json:"transportMode"`
+				} `json:"serviceJourney"`
+			} `json:"estimatedCalls"`
+		} `json:"stopPlace"`
+	} `json:"data"`
+}
+
+// Stationboard implements providers.Provider. station may be a stop place
+// name (resolved via the geocoder) or an NSR ID (e.g. "NSR:StopPlace:548").
+func (c *Client) Stationboard(station string, limit int, when time.Time) ([]providers.Departure, error) {
+	id := station
+	if !strings.HasPrefix(id, "NSR:StopPlace:") {
+		stations, err := c.SearchStations(station)
+		if err != nil {
+			return nil, fmt.Errorf("resolving station %q: %w", station, err)
+		}
+		if len(stations) == 0 || stations[0].ID == "" {
+			return nil, fmt.Errorf("no stop place found for %q", station)
+		}
+		id = stations[0].ID
+	}
+
+	numDepartures := limit
+	if numDepartures <= 0 {
+		numDepartures = 10
+	}
+	if when.IsZero() {
+		when = time.Now()
+	}
+
+	reqBody := graphQLRequest{
+		Query: stopPlaceQuery,
+		Variables: map[string]interface{}{
+			"id":            id,
+			"numDepartures": numDepartures,
+			"startTime":     when.Format(time.RFC3339),
+		},
+	}
+
+	var spr stopPlaceResponse
+	if err := c.query(reqBody, stationboardTTL, &spr); err != nil {
+		return nil, err
+	}
+
+	departures := make([]providers.Departure, 0, len(spr.Data.StopPlace.EstimatedCalls))
+	for _, call := range spr.Data.StopPlace.EstimatedCalls {
+		dep := providers.Departure{
+			Journey: providers.Journey{
+				Category: call.ServiceJourney.TransportMode,
+				Number:   call.ServiceJourney.Line.PublicCode,
+				To:       call.DestinationDisplay.FrontText,
+			},
+			Stop: providers.Stop{
+				Departure: call.AimedDepartureTime,
+				Platform:  call.Quay.PublicCode,
+			},
+		}
+		if call.Realtime && (call.ExpectedDepartureTime != "" || call.Cancellation) {
+			dep.Stop.Prognosis = &providers.Prognosis{Departure: call.ExpectedDepartureTime, Cancelled: call.Cancellation}
+		}
+		departures = append(departures, dep)
+	}
+	return departures, nil
+}
+
+// graphQLErrors mirrors the top-level "errors" array GraphQL APIs return
+// alongside (or instead of) "data" — Entur answers a bad/unresolvable query
+// with HTTP 200 and this populated, not a non-200 status.
+type graphQLErrors struct {
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// query issues a GraphQL POST against Entur and decodes the result into out.
+func (c *Client) query(body graphQLRequest, ttl time.Duration, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encoding graphql request: %w", err)
+	}
+
+	respBody, err := c.TC.PostJSON(graphQLURL, payload, ttl, map[string]string{"ET-Client-Name": clientName})
+	if err != nil {
+		return fmt.Errorf("querying entur: %w", err)
+	}
+
+	var ge graphQLErrors
+	if err := json.Unmarshal(respBody, &ge); err != nil {
+		return fmt.Errorf("parsing entur response: %w", err)
+	}
+	if len(ge.Errors) > 0 {
+		return fmt.Errorf("entur: %s", ge.Errors[0].Message)
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("parsing entur response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+// Package opendatach implements providers.Provider against the public
+// transport.opendata.ch API, which covers Swiss public transport.
+package opendatach
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/mikeshootzz/SBB-CLI/providers"
+	"github.com/mikeshootzz/SBB-CLI/providers/transportclient"
+)
+
+// baseURL is the root of the transport.opendata.ch v1 API.
+const baseURL = "http://transport.opendata.ch/v1"
+
+// Cache TTLs per endpoint: station lookups barely change, connections and
+// stationboards are only worth caching long enough to absorb repeat runs
+// within the same request burst.
+const (
+	stationsTTL     = 7 * 24 * time.Hour
+	connectionsTTL  = 60 * time.Second
+	stationboardTTL = 60 * time.Second
+)
+
+// Client talks to transport.opendata.ch on behalf of the CLI.
+type Client struct {
+	TC *transportclient.Client
+}
+
+// New returns a Client ready to query transport.opendata.ch, using tc for
+// all HTTP access so responses are cached on disk.
+func New(tc *transportclient.Client) *Client {
+	return &Client{TC: tc}
+}
+
+// Connections implements providers.Provider.
+func (c *Client) Connections(from, to string, when time.Time, opts providers.QueryOptions) ([]providers.Connection, error) {
+	v := url.Values{}
+	v.Set("from", from)
+	v.Set("to", to)
+	if !when.IsZero() {
+		v.Set("date", when.Format("2006-01-02"))
+		v.Set("time", when.Format("15:04"))
+	}
+	if opts.Limit > 0 {
+		v.Set("limit", fmt.Sprintf("%d", opts.Limit))
+	}
+
+	apiURL := fmt.Sprintf("%s/connections?%s", baseURL, v.Encode())
+	body, err := c.TC.Get(apiURL, connectionsTTL)
+	if err != nil {
+		return nil, fmt.Errorf("fetching connections: %w", err)
+	}
+
+	var apiResp providers.APIResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("parsing connections response: %w", err)
+	}
+
+	return apiResp.Connections, nil
+}
+
+// stationboardEntry mirrors one row of the /v1/stationboard payload.
+type stationboardEntry struct {
+	Category string         `json:"category"`
+	Number   string         `json:"number"`
+	To       string         `json:"to"`
+	Stop     providers.Stop `json:"stop"`
+}
+
+// stationboardResponse mirrors the /v1/stationboard payload.
+type stationboardResponse struct {
+	Stationboard []stationboardEntry `json:"stationboard"`
+}
+
+// Stationboard implements providers.Provider.
+func (c *Client) Stationboard(station string, limit int, when time.Time) ([]providers.Departure, error) {
+	v := url.Values{}
+	v.Set("station", station)
+	if limit > 0 {
+		v.Set("limit", fmt.Sprintf("%d", limit))
+	}
+	if !when.IsZero() {
+		v.Set("datetime", when.Format("2006-01-02 15:04"))
+	}
+
+	apiURL := fmt.Sprintf("%s/stationboard?%s", baseURL, v.Encode())
+	body, err := c.TC.Get(apiURL, stationboardTTL)
+	if err != nil {
+		return nil, fmt.Errorf("fetching stationboard: %w", err)
+	}
+
+	var sbResp stationboardResponse
+	if err := json.Unmarshal(body, &sbResp); err != nil {
+		return nil, fmt.Errorf("parsing stationboard response: %w", err)
+	}
+
+	departures := make([]providers.Departure, 0, len(sbResp.Stationboard))
+	for _, entry := range sbResp.Stationboard {
+		departures = append(departures, providers.Departure{
+			Journey: providers.Journey{
+				Category: entry.Category,
+				Number:   entry.Number,
+				To:       entry.To,
+			},
+			Stop: entry.Stop,
+		})
+	}
+	return departures, nil
+}
+
+// locationsResponse mirrors the /v1/locations payload.
+type locationsResponse struct {
+	Stations []providers.Station `json:"stations"`
+}
+
+// SearchStations implements providers.Provider.
+func (c *Client) SearchStations(query string) ([]providers.Station, error) {
+	v := url.Values{}
+	v.Set("query", query)
+
+	apiURL := fmt.Sprintf("%s/locations?%s", baseURL, v.Encode())
+	body, err := c.TC.Get(apiURL, stationsTTL)
+	if err != nil {
+		return nil, fmt.Errorf("fetching stations: %w", err)
+	}
+
+	var locResp locationsResponse
+	if err := json.Unmarshal(body, &locResp); err != nil {
+		return nil, fmt.Errorf("parsing stations response: %w", err)
+	}
+
+	return locResp.Stations, nil
+}
@@ -0,0 +1,98 @@
+// Package providers defines the backend-agnostic journey model and the
+// Provider interface that each transport backend (opendata.ch, Entur, ...)
+// implements.
+package providers
+
+import "time"
+
+// APIResponse represents the JSON response from the transport API.
+type APIResponse struct {
+	Connections []Connection `json:"connections"`
+}
+
+// Connection represents an overall journey.
+type Connection struct {
+	From     Stop      `json:"from"`
+	To       Stop      `json:"to"`
+	Duration string    `json:"duration"` // e.g., "00d00:55:00"
+	Sections []Section `json:"sections"`
+}
+
+// Section represents one leg (step) of a journey.
+type Section struct {
+	Departure Stop     `json:"departure"`
+	Arrival   Stop     `json:"arrival"`
+	Journey   *Journey `json:"journey"` // may be nil for a walking transfer
+}
+
+// Journey holds information about the transportation used in a section.
+type Journey struct {
+	Category string `json:"category"` // e.g., "S" or "IR"
+	Number   string `json:"number"`   // e.g., "14" or "36"
+	Operator string `json:"operator"` // not used in display
+	To       string `json:"to"`       // final destination of this leg
+}
+
+// Stop holds the details for a departure or arrival.
+type Stop struct {
+	Departure string     `json:"departure"` // ISO8601 time string
+	Arrival   string     `json:"arrival"`   // ISO8601 time string
+	Platform  string     `json:"platform"`  // planned platform
+	Station   Station    `json:"station"`
+	Prognosis *Prognosis `json:"prognosis,omitempty"`
+}
+
+// Station represents a station or stop.
+type Station struct {
+	Name string `json:"name"`
+	// ID is the backend's canonical identifier for the station, when it has
+	// one (e.g. an Entur NSR stop place ID). opendata.ch addresses stations
+	// by name alone, so ID is left empty there.
+	ID string `json:"id,omitempty"`
+}
+
+// Departure is a single row of a stationboard: one vehicle leaving a given
+// station, in the direction given by Journey.To.
+type Departure struct {
+	Journey Journey
+	Stop    Stop
+}
+
+// Prognosis holds the realtime information (if available) for a stop.
+type Prognosis struct {
+	Platform    string `json:"platform"`
+	Arrival     string `json:"arrival"`
+	Departure   string `json:"departure"`
+	Capacity1st string `json:"capacity1st"`
+	Capacity2nd string `json:"capacity2nd"`
+	// Cancelled reports whether the realtime source is reporting this stop
+	// as cancelled. Only backends that surface cancellations (Entur) set
+	// this; it stays false for backends that don't (opendata.ch).
+	Cancelled bool `json:"cancelled,omitempty"`
+}
+
+// QueryOptions carries the optional parameters a caller may set when asking
+// a Provider for connections; currently only the number of results. Fields
+// left at their zero value fall back to the provider's own defaults.
+type QueryOptions struct {
+	Limit int
+}
+
+// Provider is implemented by each transport backend (opendata.ch, Entur,
+// ...). The CLI only ever talks to a Provider, never to a backend's raw API,
+// so renderers and subcommands work unchanged regardless of which backend
+// answered the query.
+type Provider interface {
+	// Connections returns journeys from "from" to "to" departing at or after
+	// "when". A zero time.Time means "now".
+	Connections(from, to string, when time.Time, opts QueryOptions) ([]Connection, error)
+
+	// SearchStations resolves a (possibly partial) station name to the
+	// backend's canonical station list.
+	SearchStations(query string) ([]Station, error)
+
+	// Stationboard returns up to limit upcoming departures from station at
+	// or after when. A zero time.Time means "now", and limit <= 0 falls
+	// back to the provider's own default.
+	Stationboard(station string, limit int, when time.Time) ([]Departure, error)
+}
@@ -0,0 +1,202 @@
+// Package transportclient provides a disk-backed, TTL-aware HTTP client
+// shared by the provider backends so repeated queries (and offline re-runs
+// of recently-fetched journeys) don't have to hit the network every time.
+package transportclient
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// entry is what gets persisted to disk for a single cached response.
+type entry struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Body      []byte    `json:"body"`
+}
+
+// Client wraps http.Client with an on-disk response cache keyed by request
+// URL (and body, for POSTs). A zero-value CacheDir resolves lazily to
+// DefaultCacheDir on first use.
+type Client struct {
+	HTTPClient *http.Client
+	CacheDir   string
+	// NoCache disables reads from (but not writes to) the cache, mirroring
+	// the CLI's --no-cache flag.
+	NoCache bool
+}
+
+// New returns a Client using DefaultCacheDir, unless noCache is set in which
+// case cache reads are skipped entirely.
+func New(noCache bool) *Client {
+	dir, err := DefaultCacheDir()
+	if err != nil {
+		// Caching is a best-effort optimization: fall back to no caching
+		// rather than failing the whole CLI over a missing cache dir.
+		return &Client{HTTPClient: http.DefaultClient, NoCache: true}
+	}
+	return &Client{HTTPClient: http.DefaultClient, CacheDir: dir, NoCache: noCache}
+}
+
+// DefaultCacheDir returns $XDG_CACHE_HOME/sbb-cli (or the OS equivalent via
+// os.UserCacheDir), creating it if necessary.
+func DefaultCacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving cache dir: %w", err)
+	}
+	dir := filepath.Join(base, "sbb-cli")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating cache dir: %w", err)
+	}
+	return dir, nil
+}
+
+// Purge removes every cached response file directly under dir.
+// Subdirectories (such as the stats database's) are left in place.
+func Purge(dir string) error {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("reading cache dir: %w", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+			return fmt.Errorf("removing %s: %w", e.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Get performs a cached GET: a fresh (within ttl) cache hit is returned
+// without touching the network. Extra headers (e.g. an API client-name
+// header) can be passed via headers.
+func (c *Client) Get(url string, ttl time.Duration, headers ...map[string]string) ([]byte, error) {
+	key := cacheKey(http.MethodGet, url, nil)
+	if body, ok := c.load(key, ttl); ok {
+		return body, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building request to %s: %w", url, err)
+	}
+	for _, h := range headers {
+		for k, v := range h {
+			req.Header.Set(k, v)
+		}
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: received status code %d", url, resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response from %s: %w", url, err)
+	}
+
+	c.store(key, body)
+	return body, nil
+}
+
+// PostJSON performs a cached POST with a JSON body, caching on the
+// combination of url and body so distinct GraphQL queries don't collide.
+func (c *Client) PostJSON(url string, body []byte, ttl time.Duration, headers map[string]string) ([]byte, error) {
+	key := cacheKey(http.MethodPost, url, body)
+	if cached, ok := c.load(key, ttl); ok {
+		return cached, nil
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("building request to %s: %w", url, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("posting to %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("posting to %s: received status code %d", url, resp.StatusCode)
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response from %s: %w", url, err)
+	}
+
+	c.store(key, respBody)
+	return respBody, nil
+}
+
+// load returns the cached body for key if present and younger than ttl.
+func (c *Client) load(key string, ttl time.Duration) ([]byte, bool) {
+	if c.NoCache || c.CacheDir == "" {
+		return nil, false
+	}
+
+	raw, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var e entry
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return nil, false
+	}
+	if time.Since(e.FetchedAt) > ttl {
+		return nil, false
+	}
+	return e.Body, true
+}
+
+// store writes body to the cache under key. Failures are ignored: the
+// cache is an optimization, not a correctness requirement.
+func (c *Client) store(key string, body []byte) {
+	if c.CacheDir == "" {
+		return
+	}
+	raw, err := json.Marshal(entry{FetchedAt: time.Now(), Body: body})
+	if err != nil {
+		return
+	}
+	_ = ioutil.WriteFile(c.path(key), raw, 0o644)
+}
+
+func (c *Client) path(key string) string {
+	return filepath.Join(c.CacheDir, key+".json")
+}
+
+// cacheKey hashes method, url and body into a filesystem-safe identifier.
+func cacheKey(method, url string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte(url))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,149 @@
+package transportclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	return &Client{HTTPClient: http.DefaultClient, CacheDir: t.TempDir()}
+}
+
+func TestGetCachesWithinTTL(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t)
+	for i := 0; i < 3; i++ {
+		body, err := c.Get(srv.URL, time.Hour)
+		if err != nil {
+			t.Fatalf("Get returned error: %v", err)
+		}
+		if string(body) != "hello" {
+			t.Errorf("Get returned %q, want %q", body, "hello")
+		}
+	}
+	if hits != 1 {
+		t.Errorf("server was hit %d times, want 1 (later Gets should be cache hits)", hits)
+	}
+}
+
+func TestGetRefetchesAfterTTLExpires(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t)
+	if _, err := c.Get(srv.URL, -time.Second); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if _, err := c.Get(srv.URL, -time.Second); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if hits != 2 {
+		t.Errorf("server was hit %d times, want 2 (an already-expired TTL should never cache hit)", hits)
+	}
+}
+
+func TestGetNoCacheAlwaysHitsServer(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t)
+	c.NoCache = true
+	for i := 0; i < 2; i++ {
+		if _, err := c.Get(srv.URL, time.Hour); err != nil {
+			t.Fatalf("Get returned error: %v", err)
+		}
+	}
+	if hits != 2 {
+		t.Errorf("server was hit %d times, want 2 with NoCache set", hits)
+	}
+}
+
+func TestGetErrorOnNonOKStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t)
+	if _, err := c.Get(srv.URL, time.Hour); err == nil {
+		t.Fatalf("expected an error for a non-200 response")
+	}
+}
+
+func TestPostJSONCachesOnURLAndBody(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t)
+	if _, err := c.PostJSON(srv.URL, []byte(`{"a":1}`), time.Hour, nil); err != nil {
+		t.Fatalf("PostJSON returned error: %v", err)
+	}
+	if _, err := c.PostJSON(srv.URL, []byte(`{"a":1}`), time.Hour, nil); err != nil {
+		t.Fatalf("PostJSON returned error: %v", err)
+	}
+	if _, err := c.PostJSON(srv.URL, []byte(`{"a":2}`), time.Hour, nil); err != nil {
+		t.Fatalf("PostJSON returned error: %v", err)
+	}
+	if hits != 2 {
+		t.Errorf("server was hit %d times, want 2 (same body cached, different body doesn't)", hits)
+	}
+}
+
+func TestPurgeRemovesAllCachedEntries(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t)
+	if _, err := c.Get(srv.URL, time.Hour); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+
+	if err := Purge(c.CacheDir); err != nil {
+		t.Fatalf("Purge returned error: %v", err)
+	}
+
+	if _, ok := c.load(cacheKey(http.MethodGet, srv.URL, nil), time.Hour); ok {
+		t.Errorf("expected cache entry to be gone after Purge")
+	}
+}
+
+func TestPurgeMissingDirIsNotAnError(t *testing.T) {
+	if err := Purge("/nonexistent/does/not/exist"); err != nil {
+		t.Errorf("Purge on a missing dir returned error: %v, want nil", err)
+	}
+}
+
+func TestCacheKeyDistinguishesMethodURLAndBody(t *testing.T) {
+	a := cacheKey(http.MethodGet, "http://x", nil)
+	b := cacheKey(http.MethodPost, "http://x", nil)
+	c := cacheKey(http.MethodGet, "http://y", nil)
+	d := cacheKey(http.MethodPost, "http://x", []byte("body"))
+
+	keys := map[string]bool{a: true, b: true, c: true, d: true}
+	if len(keys) != 4 {
+		t.Errorf("expected 4 distinct cache keys, got %d", len(keys))
+	}
+}
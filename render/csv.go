@@ -0,0 +1,95 @@
+package render
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/mikeshootzz/SBB-CLI/providers"
+)
+
+// csvRenderer emits one row per section, GTFS-adjacent so it's easy to pull
+// into a spreadsheet or join against a GTFS feed.
+type csvRenderer struct{}
+
+var connectionsCSVHeader = []string{
+	"connection", "leg", "category", "line", "from",
+	"dep_sched", "dep_real", "platform", "to",
+	"arr_sched", "arr_real", "platform", "delay_min",
+}
+
+func (csvRenderer) Connections(w io.Writer, conns []providers.Connection) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(connectionsCSVHeader); err != nil {
+		return err
+	}
+
+	for i, conn := range conns {
+		for j, sec := range conn.Sections {
+			category, line := "", ""
+			if sec.Journey != nil {
+				category, line = sec.Journey.Category, sec.Journey.Number
+			}
+			record := []string{
+				fmt.Sprintf("%d", i+1),
+				fmt.Sprintf("%d", j+1),
+				category,
+				line,
+				sec.Departure.Station.Name,
+				sec.Departure.Departure,
+				prognosisTime(sec.Departure.Prognosis, true),
+				sec.Departure.Platform,
+				sec.Arrival.Station.Name,
+				sec.Arrival.Arrival,
+				prognosisTime(sec.Arrival.Prognosis, false),
+				sec.Arrival.Platform,
+				fmt.Sprintf("%d", stopDelayMinutes(sec.Arrival, false)),
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+var departuresCSVHeader = []string{"category", "line", "to", "dep_sched", "dep_real", "platform", "delay_min"}
+
+func (csvRenderer) Departures(w io.Writer, station string, deps []providers.Departure) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(departuresCSVHeader); err != nil {
+		return err
+	}
+
+	for _, dep := range deps {
+		record := []string{
+			dep.Journey.Category,
+			dep.Journey.Number,
+			dep.Journey.To,
+			dep.Stop.Departure,
+			prognosisTime(dep.Stop.Prognosis, true),
+			dep.Stop.Platform,
+			fmt.Sprintf("%d", stopDelayMinutes(dep.Stop, true)),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// prognosisTime returns the realtime estimate from a Prognosis (departure
+// or arrival, per isDeparture), or "" if there is none.
+func prognosisTime(p *providers.Prognosis, isDeparture bool) string {
+	if p == nil {
+		return ""
+	}
+	if isDeparture {
+		return p.Departure
+	}
+	return p.Arrival
+}
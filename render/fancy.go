@@ -0,0 +1,222 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/mikeshootzz/SBB-CLI/providers"
+)
+
+// fancyRenderer is the original ASCII-art timeline output and is the
+// default when --output isn't given.
+type fancyRenderer struct{}
+
+func (fancyRenderer) Connections(w io.Writer, conns []providers.Connection) error {
+	fmt.Fprint(w, `
+   ____  _     _       ____           _
+  / ___|| |_  (_) ___ |  _ \ ___  ___| |_ ___  _ __
+  \___ \| __| | |/ __|| |_) / _ \/ __| __/ _ \| '__|
+   ___) | |_  | |\__ \|  _ <  __/\__ \ || (_) | |
+  |____/ \__| |_||___/|_| \_\___||___/\__\___/|_|
+
+🚆  Welcome to Transport CLI 🚏
+`)
+
+	if len(conns) == 0 {
+		fmt.Fprintln(w, "No connections found.")
+		return nil
+	}
+
+	for i, conn := range conns {
+		fmt.Fprintf(w, "\nConnection %d: Overall Duration: %s\n", i+1, formatDurationString(conn.Duration))
+		if len(conn.Sections) > 0 {
+			fmt.Fprint(w, displayFancyTimeline(conn.Sections))
+		} else {
+			// Fallback if no sections available.
+			fmt.Fprintf(w, "%s\n", formatStopFancy(conn.From, true))
+			fmt.Fprintf(w, "  ──( Walk )──▶\n")
+			fmt.Fprintf(w, "%s\n", formatStopFancy(conn.To, false))
+		}
+		fmt.Fprintln(w, "--------------------------------")
+	}
+	return nil
+}
+
+func (fancyRenderer) Departures(w io.Writer, station string, deps []providers.Departure) error {
+	if len(deps) == 0 {
+		fmt.Fprintln(w, "No departures found.")
+		return nil
+	}
+
+	fmt.Fprintf(w, "\nDepartures from %s:\n\n", station)
+	for _, dep := range deps {
+		fmt.Fprintln(w, formatDepartureFancy(dep))
+	}
+	return nil
+}
+
+// displayFancyTimeline builds a multi-line, left-to-right timeline for the connection's sections.
+func displayFancyTimeline(sections []providers.Section) string {
+	var builder strings.Builder
+	// Print the first stop using its departure details.
+	builder.WriteString(formatStopFancy(sections[0].Departure, true) + "\n")
+	// For each section, print the journey and the arrival stop.
+	for _, sec := range sections {
+		builder.WriteString("    " + formatJourneyFancy(sec.Journey) + "\n")
+		builder.WriteString(formatStopFancy(sec.Arrival, false) + "\n")
+	}
+	return builder.String()
+}
+
+// formatStopFancy returns a formatted string for a stop, with the time
+// colored and annotated per its realtime StopStatus and a platform-change
+// warning if the platform moved.
+// isDeparture flag indicates whether this is a departure (true) or arrival (false) stop.
+func formatStopFancy(stop providers.Stop, isDeparture bool) string {
+	status := stopStatus(stop, isDeparture)
+	platformNote := ""
+	if status.PlatformChanged {
+		platformNote = " ⚠️"
+	}
+	return fmt.Sprintf("[ %s (%s | Plat %s%s) ]",
+		stop.Station.Name,
+		coloredTime(stop, isDeparture, status),
+		stop.Platform,
+		platformNote,
+	)
+}
+
+// coloredTime renders a stop's time plus a "+Nmin"/"CANCELLED" annotation,
+// colored green for an on-time realtime stop, yellow for a 1-3 minute
+// delay, and red for anything later or cancelled.
+func coloredTime(stop providers.Stop, isDeparture bool, status StopStatus) string {
+	t := formatTimeString(stop.Departure)
+	if !isDeparture {
+		t = formatTimeString(stop.Arrival)
+	}
+
+	switch {
+	case status.Cancelled:
+		t += " CANCELLED"
+	case status.DelaySeconds > 0:
+		t += fmt.Sprintf(" +%dmin", status.DelaySeconds/60)
+	}
+
+	c := colorForStatus(status)
+	if c == nil {
+		return t
+	}
+	return c.Sprint(t)
+}
+
+// colorForStatus picks the color for a StopStatus, or nil for "don't
+// color this" (no realtime data to judge by).
+func colorForStatus(status StopStatus) *color.Color {
+	switch {
+	case status.Cancelled || status.DelaySeconds > 180:
+		return color.New(color.FgRed)
+	case status.DelaySeconds >= 60:
+		return color.New(color.FgYellow)
+	case status.IsRealtime:
+		return color.New(color.FgGreen)
+	default:
+		return nil
+	}
+}
+
+// formatJourneyFancy returns a formatted string for a journey segment, omitting any internal id.
+func formatJourneyFancy(journey *providers.Journey) string {
+	if journey == nil {
+		return "──( Walk )──▶"
+	}
+	// Display only the category and line number (e.g., "S 14")
+	return fmt.Sprintf("──( %s %s )──▶", journey.Category, journey.Number)
+}
+
+// formatDepartureFancy renders one stationboard row: line, destination,
+// time (colored and annotated per the same StopStatus logic as the
+// connections view), and platform.
+func formatDepartureFancy(dep providers.Departure) string {
+	status := stopStatus(dep.Stop, true)
+	platformNote := ""
+	if status.PlatformChanged {
+		platformNote = " ⚠️"
+	}
+	return fmt.Sprintf("[ %s %-4s ] %-24s (%s | Plat %s%s)",
+		dep.Journey.Category,
+		dep.Journey.Number,
+		dep.Journey.To,
+		coloredTime(dep.Stop, true, status),
+		dep.Stop.Platform,
+		platformNote,
+	)
+}
+
+// formatDurationString converts a duration like "00d00:55:00" into a human-friendly string.
+func formatDurationString(dur string) string {
+	// Expected format: "00d00:55:00" => days 'd' then HH:MM:SS.
+	parts := strings.SplitN(dur, "d", 2)
+	if len(parts) != 2 {
+		return dur
+	}
+	daysStr := parts[0]
+	timePart := parts[1]
+	days, err := strconv.Atoi(daysStr)
+	if err != nil {
+		return dur
+	}
+	tparts := strings.Split(timePart, ":")
+	if len(tparts) != 3 {
+		return dur
+	}
+	hours, err := strconv.Atoi(tparts[0])
+	if err != nil {
+		return dur
+	}
+	minutes, err := strconv.Atoi(tparts[1])
+	if err != nil {
+		return dur
+	}
+	seconds, err := strconv.Atoi(tparts[2])
+	if err != nil {
+		return dur
+	}
+	var partsOut []string
+	if days > 0 {
+		if days == 1 {
+			partsOut = append(partsOut, fmt.Sprintf("%d day", days))
+		} else {
+			partsOut = append(partsOut, fmt.Sprintf("%d days", days))
+		}
+	}
+	if hours > 0 {
+		if hours == 1 {
+			partsOut = append(partsOut, fmt.Sprintf("%d hour", hours))
+		} else {
+			partsOut = append(partsOut, fmt.Sprintf("%d hours", hours))
+		}
+	}
+	if minutes > 0 {
+		if minutes == 1 {
+			partsOut = append(partsOut, fmt.Sprintf("%d minute", minutes))
+		} else {
+			partsOut = append(partsOut, fmt.Sprintf("%d minutes", minutes))
+		}
+	}
+	// Only show seconds if no other unit is significant.
+	if seconds > 0 && days == 0 && hours == 0 && minutes == 0 {
+		if seconds == 1 {
+			partsOut = append(partsOut, fmt.Sprintf("%d second", seconds))
+		} else {
+			partsOut = append(partsOut, fmt.Sprintf("%d seconds", seconds))
+		}
+	}
+	if len(partsOut) == 0 {
+		return "0 minutes"
+	}
+	return strings.Join(partsOut, " ")
+}
@@ -0,0 +1,90 @@
+package render
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/mikeshootzz/SBB-CLI/providers"
+)
+
+// connectionView is a Connection plus the fields scripts actually want to
+// filter/sort on without recomputing them from raw times.
+type connectionView struct {
+	providers.Connection
+	DelayMinutes int  `json:"delay_minutes"`
+	Realtime     bool `json:"realtime"`
+	Transfers    int  `json:"transfers"`
+}
+
+func newConnectionView(conn providers.Connection) connectionView {
+	return connectionView{
+		Connection:   conn,
+		DelayMinutes: delayMinutes(conn),
+		Realtime:     isRealtime(conn),
+		Transfers:    transfers(conn),
+	}
+}
+
+// departureView is a Departure plus its derived realtime delay.
+type departureView struct {
+	providers.Departure
+	DelayMinutes int  `json:"delay_minutes"`
+	Realtime     bool `json:"realtime"`
+}
+
+func newDepartureView(dep providers.Departure) departureView {
+	return departureView{
+		Departure:    dep,
+		DelayMinutes: stopDelayMinutes(dep.Stop, true),
+		Realtime:     dep.Stop.Prognosis != nil,
+	}
+}
+
+// jsonRenderer emits one JSON document holding every result, for tools that
+// want to load the whole response at once.
+type jsonRenderer struct{}
+
+func (jsonRenderer) Connections(w io.Writer, conns []providers.Connection) error {
+	views := make([]connectionView, len(conns))
+	for i, c := range conns {
+		views[i] = newConnectionView(c)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(views)
+}
+
+func (jsonRenderer) Departures(w io.Writer, station string, deps []providers.Departure) error {
+	views := make([]departureView, len(deps))
+	for i, d := range deps {
+		views[i] = newDepartureView(d)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(views)
+}
+
+// ndjsonRenderer streams one JSON document per result, one per line, so
+// pipelines like `sbb ZRH BRN -o ndjson | jq` can process results as they
+// arrive instead of waiting for the whole array.
+type ndjsonRenderer struct{}
+
+func (ndjsonRenderer) Connections(w io.Writer, conns []providers.Connection) error {
+	enc := json.NewEncoder(w)
+	for _, c := range conns {
+		if err := enc.Encode(newConnectionView(c)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ndjsonRenderer) Departures(w io.Writer, station string, deps []providers.Departure) error {
+	enc := json.NewEncoder(w)
+	for _, d := range deps {
+		if err := enc.Encode(newDepartureView(d)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// Package render turns provider results into CLI output. Each output format
+// (fancy ASCII art, JSON, NDJSON, CSV, plain table) implements Renderer, so
+// adding a new format doesn't touch the command code that calls it.
+package render
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mikeshootzz/SBB-CLI/providers"
+)
+
+// Renderer writes query results to w in a particular output format.
+type Renderer interface {
+	Connections(w io.Writer, conns []providers.Connection) error
+	Departures(w io.Writer, station string, deps []providers.Departure) error
+}
+
+// New returns the Renderer registered under format ("fancy", "table",
+// "json", "ndjson", "csv").
+func New(format string) (Renderer, error) {
+	switch format {
+	case "", "fancy":
+		return fancyRenderer{}, nil
+	case "table":
+		return tableRenderer{}, nil
+	case "json":
+		return jsonRenderer{}, nil
+	case "ndjson":
+		return ndjsonRenderer{}, nil
+	case "csv":
+		return csvRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q (want one of: fancy, table, json, ndjson, csv)", format)
+	}
+}
+
+// transfers counts the vehicle legs in a connection (walking transfers have
+// a nil Journey and don't count) and returns the number of changes between
+// them.
+func transfers(conn providers.Connection) int {
+	legs := 0
+	for _, s := range conn.Sections {
+		if s.Journey != nil {
+			legs++
+		}
+	}
+	if legs == 0 {
+		return 0
+	}
+	return legs - 1
+}
+
+// isRealtime reports whether the connection's final arrival carries a live
+// estimate.
+func isRealtime(conn providers.Connection) bool {
+	if len(conn.Sections) == 0 {
+		return false
+	}
+	return conn.Sections[len(conn.Sections)-1].Arrival.Prognosis != nil
+}
+
+// delayMinutes returns how many minutes late the connection's final arrival
+// is expected to be, based on its Prognosis vs scheduled time. 0 if there's
+// no realtime estimate.
+func delayMinutes(conn providers.Connection) int {
+	if len(conn.Sections) == 0 {
+		return 0
+	}
+	return stopStatus(conn.Sections[len(conn.Sections)-1].Arrival, false).DelaySeconds / 60
+}
+
+// stopDelayMinutes compares a stop's scheduled time (arrival, or departure
+// if isDeparture) to its realtime estimate, if any.
+func stopDelayMinutes(stop providers.Stop, isDeparture bool) int {
+	return stopStatus(stop, isDeparture).DelaySeconds / 60
+}
+
+// StopStatus is the realtime picture for a single stop: how late it's
+// running, whether the platform changed, and whether it's been cancelled.
+type StopStatus struct {
+	DelaySeconds    int
+	PlatformChanged bool
+	Cancelled       bool
+	IsRealtime      bool
+}
+
+// stopStatus computes StopStatus for a stop's departure (isDeparture) or
+// arrival side from its scheduled time vs Prognosis.
+func stopStatus(stop providers.Stop, isDeparture bool) StopStatus {
+	if stop.Prognosis == nil {
+		return StopStatus{}
+	}
+
+	scheduled, realtime := stop.Arrival, stop.Prognosis.Arrival
+	if isDeparture {
+		scheduled, realtime = stop.Departure, stop.Prognosis.Departure
+	}
+
+	status := StopStatus{
+		Cancelled:       stop.Prognosis.Cancelled,
+		PlatformChanged: stop.Prognosis.Platform != "" && stop.Prognosis.Platform != stop.Platform,
+		IsRealtime:      realtime != "",
+	}
+
+	if status.IsRealtime && scheduled != "" {
+		schedT, err1 := parseTime(scheduled)
+		realT, err2 := parseTime(realtime)
+		if err1 == nil && err2 == nil {
+			status.DelaySeconds = int(realT.Sub(schedT).Seconds())
+		}
+	}
+	return status
+}
+
+// parseTime parses an opendata.ch/Entur ISO8601-ish timestamp, trying the
+// RFC3339 layout first and falling back to the colon-less offset variant
+// opendata.ch sometimes returns.
+func parseTime(t string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, t); err == nil {
+		return parsed, nil
+	}
+	return time.Parse("2006-01-02T15:04:05-0700", t)
+}
+
+// formatTimeString converts an ISO8601 time string to a "15:04" format,
+// returning the original string if it can't be parsed.
+func formatTimeString(t string) string {
+	if t == "" {
+		return ""
+	}
+	parsed, err := parseTime(t)
+	if err != nil {
+		return t
+	}
+	return parsed.Format("15:04")
+}
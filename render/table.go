@@ -0,0 +1,57 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/mikeshootzz/SBB-CLI/providers"
+)
+
+// tableRenderer prints plain, aligned columns with no ASCII art or emoji —
+// for terminals/logs where "fancy" output is noise.
+type tableRenderer struct{}
+
+func (tableRenderer) Connections(w io.Writer, conns []providers.Connection) error {
+	if len(conns) == 0 {
+		fmt.Fprintln(w, "No connections found.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONN\tLEG\tLINE\tFROM\tDEP\tTO\tARR\tDELAY")
+	for i, conn := range conns {
+		for j, sec := range conn.Sections {
+			line := "Walk"
+			if sec.Journey != nil {
+				line = fmt.Sprintf("%s %s", sec.Journey.Category, sec.Journey.Number)
+			}
+			fmt.Fprintf(tw, "%d\t%d\t%s\t%s\t%s\t%s\t%s\t%+dmin\n",
+				i+1, j+1, line,
+				sec.Departure.Station.Name, formatTimeString(sec.Departure.Departure),
+				sec.Arrival.Station.Name, formatTimeString(sec.Arrival.Arrival),
+				stopDelayMinutes(sec.Arrival, false),
+			)
+		}
+	}
+	return tw.Flush()
+}
+
+func (tableRenderer) Departures(w io.Writer, station string, deps []providers.Departure) error {
+	if len(deps) == 0 {
+		fmt.Fprintln(w, "No departures found.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(tw, "LINE\tTO\tDEP\tPLATFORM")
+	for _, dep := range deps {
+		fmt.Fprintf(tw, "%s %s\t%s\t%s\t%s\n",
+			dep.Journey.Category, dep.Journey.Number,
+			dep.Journey.To,
+			formatTimeString(dep.Stop.Departure),
+			dep.Stop.Platform,
+		)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,12 @@
+package stations
+
+import _ "embed"
+
+// seedNDJSON is a small, embedded set of major Swiss hubs so the CLI has
+// something to resolve against before the first `locations` fetch or a
+// --stations-file load of a fuller offline list. It intentionally stays
+// small: anything beyond the handful of busiest stations is better fetched
+// from the provider on demand (see ResolveStation) or bulk-loaded from a file.
+//
+//go:embed seed.ndjson
+var seedNDJSON string
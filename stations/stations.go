@@ -0,0 +1,270 @@
+// Package stations resolves user-typed station fragments ("zrh hb", "bern")
+// against a local, in-memory index so the CLI doesn't require the exact
+// name opendata.ch expects. The index is seeded from an embedded NDJSON
+// file, can be bulk-loaded from an offline NDJSON dump, and falls back to
+// a live provider lookup when nothing local matches.
+package stations
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mikeshootzz/SBB-CLI/providers"
+)
+
+// commonAbbreviations maps the short codes frequent flyers already know
+// (airport/city codes, SBB platform shorthand) onto the name fragment they
+// stand for, so "zrh hb" resolves the same way "zürich hb" would.
+var commonAbbreviations = map[string]string{
+	"zrh": "zurich",
+	"gva": "geneve",
+	"bsl": "basel",
+	"brn": "bern",
+	"lug": "lugano",
+	"lz":  "luzern",
+}
+
+// Match is a candidate station together with how well it scored against
+// the query, highest first.
+type Match struct {
+	Station providers.Station
+	Score   int
+}
+
+// Index is an in-memory, trigram-backed fuzzy index over a set of stations.
+type Index struct {
+	stations []providers.Station
+	// trigrams maps each 3-character shingle of a normalized station name
+	// to the indices into stations whose name contains it.
+	trigrams map[string]map[int]struct{}
+}
+
+// NewIndex returns an empty Index.
+func NewIndex() *Index {
+	return &Index{trigrams: make(map[string]map[int]struct{})}
+}
+
+// NewSeededIndex returns an Index preloaded with the embedded seed list of
+// major Swiss hubs.
+func NewSeededIndex() (*Index, error) {
+	idx := NewIndex()
+	if err := idx.LoadNDJSON(strings.NewReader(seedNDJSON)); err != nil {
+		return nil, fmt.Errorf("loading seed stations: %w", err)
+	}
+	return idx, nil
+}
+
+// Add inserts a station into the index.
+func (idx *Index) Add(st providers.Station) {
+	i := len(idx.stations)
+	idx.stations = append(idx.stations, st)
+	for _, tri := range trigrams(normalize(st.Name)) {
+		if idx.trigrams[tri] == nil {
+			idx.trigrams[tri] = make(map[int]struct{})
+		}
+		idx.trigrams[tri][i] = struct{}{}
+	}
+}
+
+// LoadNDJSON bulk-loads stations from a newline-delimited JSON stream of
+// {"name": "..."} objects, such as the project's embedded seed file or a
+// user-supplied offline station list.
+func (idx *Index) LoadNDJSON(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var st providers.Station
+		if err := json.Unmarshal([]byte(line), &st); err != nil {
+			return fmt.Errorf("parsing station line %q: %w", line, err)
+		}
+		idx.Add(st)
+	}
+	return scanner.Err()
+}
+
+// LoadFile bulk-loads stations from an NDJSON file on disk.
+func (idx *Index) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening station list %s: %w", path, err)
+	}
+	defer f.Close()
+	return idx.LoadNDJSON(f)
+}
+
+// Search returns stations scored against query, highest score first. A
+// query is expanded through commonAbbreviations before scoring so short
+// codes like "zrh" match full names like "Zürich HB".
+func (idx *Index) Search(query string) []Match {
+	expanded := expandQuery(normalize(query))
+	want := trigramSet(expanded)
+
+	scores := make(map[int]int, len(want))
+	for tri := range want {
+		for i := range idx.trigrams[tri] {
+			scores[i]++
+		}
+	}
+
+	matches := make([]Match, 0, len(scores))
+	for i, score := range scores {
+		name := normalize(idx.stations[i].Name)
+		if strings.HasPrefix(name, expanded) || hasPrefixToken(name, expanded) {
+			score += 10 // prefix matches are a much stronger signal than shingle overlap
+		}
+		matches = append(matches, Match{Station: idx.stations[i], Score: score})
+	}
+
+	sortMatchesDescending(matches)
+	return matches
+}
+
+// hasPrefixToken reports whether any whitespace-separated token of name
+// starts with prefix (e.g. "bern" matching the second word of "bahnhof bern").
+func hasPrefixToken(name, prefix string) bool {
+	for _, tok := range strings.Fields(name) {
+		if strings.HasPrefix(tok, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandQuery rewrites each whitespace-separated token of a normalized
+// query through commonAbbreviations, leaving unknown tokens untouched.
+func expandQuery(normalized string) string {
+	tokens := strings.Fields(normalized)
+	for i, tok := range tokens {
+		if full, ok := commonAbbreviations[tok]; ok {
+			tokens[i] = full
+		}
+	}
+	return strings.Join(tokens, " ")
+}
+
+// sortMatchesDescending sorts matches by Score, highest first, breaking
+// ties by station name for a stable, predictable picker order.
+func sortMatchesDescending(matches []Match) {
+	for i := 1; i < len(matches); i++ {
+		for j := i; j > 0; j-- {
+			a, b := matches[j-1], matches[j]
+			if a.Score < b.Score || (a.Score == b.Score && a.Station.Name > b.Station.Name) {
+				matches[j-1], matches[j] = matches[j], matches[j-1]
+			} else {
+				break
+			}
+		}
+	}
+}
+
+// normalize lowercases and accent-folds a station name so "Zürich" and
+// "zurich" shingle identically.
+func normalize(s string) string {
+	s = strings.ToLower(s)
+	s = accentFolder.Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+var accentFolder = strings.NewReplacer(
+	"ü", "u", "ö", "o", "ä", "a",
+	"é", "e", "è", "e", "ê", "e",
+	"à", "a", "â", "a",
+	"ç", "c",
+)
+
+// trigrams splits a normalized string into overlapping 3-character shingles,
+// padding the edges so short names still produce at least one shingle.
+func trigrams(s string) []string {
+	padded := "  " + s + " "
+	if len(padded) < 3 {
+		return nil
+	}
+	out := make([]string, 0, len(padded)-2)
+	for i := 0; i+3 <= len(padded); i++ {
+		out = append(out, padded[i:i+3])
+	}
+	return out
+}
+
+func trigramSet(s string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, tri := range trigrams(s) {
+		set[tri] = struct{}{}
+	}
+	return set
+}
+
+// ambiguityMargin is how much higher the best match's score must be over
+// the runner-up's before ResolveStation treats it as unambiguous.
+const ambiguityMargin = 5
+
+// ResolveStation finds the best station for query, first against idx and,
+// if nothing scores, against provider.SearchStations (whose results are
+// folded back into idx so later lookups stay local). It returns the best
+// match plus the full list of alternatives; callers should prompt the user
+// via PickStation when len(alternatives) > 1 and the match is ambiguous.
+func ResolveStation(idx *Index, provider providers.Provider, query string) (providers.Station, []providers.Station, error) {
+	matches := idx.Search(query)
+
+	if len(matches) == 0 && provider != nil {
+		found, err := provider.SearchStations(query)
+		if err != nil {
+			return providers.Station{}, nil, fmt.Errorf("searching stations for %q: %w", query, err)
+		}
+		for _, st := range found {
+			idx.Add(st)
+		}
+		matches = idx.Search(query)
+	}
+
+	if len(matches) == 0 {
+		return providers.Station{}, nil, fmt.Errorf("no stations found for %q", query)
+	}
+
+	alternatives := make([]providers.Station, len(matches))
+	for i, m := range matches {
+		alternatives[i] = m.Station
+	}
+	return alternatives[0], alternatives, nil
+}
+
+// IsAmbiguous reports whether alternatives (as returned by ResolveStation)
+// are close enough in rank that the caller should prompt the user instead
+// of silently picking the first one.
+func IsAmbiguous(idx *Index, query string) bool {
+	matches := idx.Search(query)
+	if len(matches) < 2 {
+		return false
+	}
+	return matches[0].Score-matches[1].Score < ambiguityMargin
+}
+
+// PickStation prints a numbered picker for alternatives and reads the
+// user's choice from in, returning the selected station.
+func PickStation(in io.Reader, alternatives []providers.Station) (providers.Station, error) {
+	for i, st := range alternatives {
+		fmt.Printf("  %d) %s\n", i+1, st.Name)
+	}
+	fmt.Print("Which station did you mean? ")
+
+	scanner := bufio.NewScanner(in)
+	if !scanner.Scan() {
+		return providers.Station{}, fmt.Errorf("reading selection: %w", scanner.Err())
+	}
+
+	var choice int
+	if _, err := fmt.Sscanf(strings.TrimSpace(scanner.Text()), "%d", &choice); err != nil {
+		return providers.Station{}, fmt.Errorf("invalid selection %q", scanner.Text())
+	}
+	if choice < 1 || choice > len(alternatives) {
+		return providers.Station{}, fmt.Errorf("selection %d out of range", choice)
+	}
+	return alternatives[choice-1], nil
+}
@@ -0,0 +1,140 @@
+package stations
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mikeshootzz/SBB-CLI/providers"
+)
+
+// fakeProvider is a minimal providers.Provider for exercising the
+// ResolveStation fallback path without a network call.
+type fakeProvider struct {
+	found []providers.Station
+	err   error
+}
+
+func (p *fakeProvider) Connections(from, to string, when time.Time, opts providers.QueryOptions) ([]providers.Connection, error) {
+	return nil, nil
+}
+
+func (p *fakeProvider) SearchStations(query string) ([]providers.Station, error) {
+	return p.found, p.err
+}
+
+func (p *fakeProvider) Stationboard(station string, limit int, when time.Time) ([]providers.Departure, error) {
+	return nil, nil
+}
+
+func TestNormalizeFoldsAccentsAndCase(t *testing.T) {
+	got := normalize("Zürich HB")
+	want := "zurich hb"
+	if got != want {
+		t.Errorf("normalize(%q) = %q, want %q", "Zürich HB", got, want)
+	}
+}
+
+func TestSearchExpandsAbbreviations(t *testing.T) {
+	idx := NewIndex()
+	idx.Add(providers.Station{Name: "Zürich HB"})
+	idx.Add(providers.Station{Name: "Bern"})
+
+	matches := idx.Search("zrh")
+	if len(matches) == 0 || matches[0].Station.Name != "Zürich HB" {
+		t.Fatalf("Search(%q) = %v, want top match Zürich HB", "zrh", matches)
+	}
+}
+
+func TestSearchPrefixTokenBeatsShingleOverlap(t *testing.T) {
+	idx := NewIndex()
+	idx.Add(providers.Station{Name: "Bahnhof Bern"})
+	idx.Add(providers.Station{Name: "Biel/Bienne"})
+
+	matches := idx.Search("bern")
+	if len(matches) == 0 || matches[0].Station.Name != "Bahnhof Bern" {
+		t.Fatalf("Search(%q) = %v, want top match Bahnhof Bern", "bern", matches)
+	}
+}
+
+func TestResolveStationPrefersLocalIndex(t *testing.T) {
+	idx := NewIndex()
+	idx.Add(providers.Station{Name: "Bern"})
+	p := &fakeProvider{}
+
+	best, alts, err := ResolveStation(idx, p, "bern")
+	if err != nil {
+		t.Fatalf("ResolveStation returned error: %v", err)
+	}
+	if best.Name != "Bern" {
+		t.Errorf("best = %q, want Bern", best.Name)
+	}
+	if len(alts) == 0 {
+		t.Errorf("expected at least one alternative")
+	}
+}
+
+func TestResolveStationFallsBackToProvider(t *testing.T) {
+	idx := NewIndex()
+	p := &fakeProvider{found: []providers.Station{{Name: "Oslo S", ID: "NSR:StopPlace:1"}}}
+
+	best, _, err := ResolveStation(idx, p, "oslo")
+	if err != nil {
+		t.Fatalf("ResolveStation returned error: %v", err)
+	}
+	if best.Name != "Oslo S" || best.ID != "NSR:StopPlace:1" {
+		t.Errorf("best = %+v, want Oslo S/NSR:StopPlace:1", best)
+	}
+
+	// The provider result should have been folded back into idx for later,
+	// purely local lookups.
+	if matches := idx.Search("oslo"); len(matches) == 0 {
+		t.Errorf("expected provider result to be cached into idx")
+	}
+}
+
+func TestResolveStationNoMatches(t *testing.T) {
+	idx := NewIndex()
+	p := &fakeProvider{found: nil}
+
+	if _, _, err := ResolveStation(idx, p, "nowhere"); err == nil {
+		t.Fatalf("expected an error when nothing matches")
+	}
+}
+
+func TestIsAmbiguous(t *testing.T) {
+	idx := NewIndex()
+	idx.Add(providers.Station{Name: "Aarau"})
+	idx.Add(providers.Station{Name: "Aarberg"})
+
+	if !IsAmbiguous(idx, "aar") {
+		t.Errorf("expected \"aar\" to be ambiguous between Aarau and Aarberg")
+	}
+	if IsAmbiguous(idx, "aarau") {
+		t.Errorf("expected an exact-ish match not to be ambiguous")
+	}
+}
+
+func TestPickStation(t *testing.T) {
+	alts := []providers.Station{{Name: "Aarau"}, {Name: "Aarberg"}}
+	picked, err := PickStation(strings.NewReader("2\n"), alts)
+	if err != nil {
+		t.Fatalf("PickStation returned error: %v", err)
+	}
+	if picked.Name != "Aarberg" {
+		t.Errorf("picked = %q, want Aarberg", picked.Name)
+	}
+}
+
+func TestLoadNDJSON(t *testing.T) {
+	idx := NewIndex()
+	data := `{"name":"Luzern"}
+{"name":"Lugano"}
+`
+	if err := idx.LoadNDJSON(strings.NewReader(data)); err != nil {
+		t.Fatalf("LoadNDJSON returned error: %v", err)
+	}
+	if matches := idx.Search("luzern"); len(matches) == 0 || matches[0].Station.Name != "Luzern" {
+		t.Errorf("Search(%q) = %v, want top match Luzern", "luzern", matches)
+	}
+}
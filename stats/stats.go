@@ -0,0 +1,251 @@
+// Package stats records realtime Prognosis observations to a local SQLite
+// database and predicts journey times from the resulting history, bucketed
+// per (line, hour of day, weekday) so a prediction reflects "Monday evening
+// rush hour" rather than an all-day average.
+package stats
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/mikeshootzz/SBB-CLI/providers"
+)
+
+// rollingWindow bounds how much history the database keeps: observations
+// older than this are purged on every Observe call, so the DB self-trims
+// instead of growing forever.
+const rollingWindow = 90 * 24 * time.Hour
+
+const schemaSQL = `
+CREATE TABLE IF NOT EXISTS observations (
+	line          TEXT NOT NULL,
+	from_id       TEXT NOT NULL,
+	to_id         TEXT NOT NULL,
+	scheduled_dep TEXT NOT NULL,
+	actual_dep    TEXT NOT NULL,
+	scheduled_arr TEXT NOT NULL,
+	actual_arr    TEXT NOT NULL,
+	observed_at   TEXT NOT NULL
+);`
+
+const insertSQL = `
+INSERT INTO observations (line, from_id, to_id, scheduled_dep, actual_dep, scheduled_arr, actual_arr, observed_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+// Recorder persists realtime observations to a SQLite database, one row per
+// vehicle leg that had a confirmed realtime departure and arrival.
+type Recorder struct {
+	db *sql.DB
+}
+
+// NewRecorder opens (creating if necessary) the SQLite database at path and
+// migrates it to the current schema.
+func NewRecorder(path string) (*Recorder, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening stats db: %w", err)
+	}
+	if _, err := db.Exec(schemaSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrating stats db: %w", err)
+	}
+	return &Recorder{db: db}, nil
+}
+
+// DefaultDBPath returns $XDG_CACHE_HOME/sbb-cli/stats/stats.db (or the OS
+// equivalent via os.UserCacheDir), creating the containing directory if
+// necessary. This lives in its own "stats" subdirectory, separate from
+// transportclient's HTTP cache files, so `sbb cache purge` (which removes
+// the files directly under sbb-cli) can't take 90 days of recorded
+// observation history with it.
+func DefaultDBPath() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving stats db path: %w", err)
+	}
+	dir := filepath.Join(base, "sbb-cli", "stats")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating stats dir: %w", err)
+	}
+	return filepath.Join(dir, "stats.db"), nil
+}
+
+// Close releases the underlying database handle.
+func (r *Recorder) Close() error {
+	return r.db.Close()
+}
+
+// Observe records one row per vehicle leg of conn whose departure and
+// arrival both carry a confirmed realtime estimate. Legs with no Prognosis
+// (either end) are skipped: without a realtime confirmation there's no
+// actual time to compare against the schedule. Walking transfers (nil
+// Journey) are skipped too, since there's no line to bucket them under.
+func (r *Recorder) Observe(conn providers.Connection) error {
+	now := time.Now()
+	for _, sec := range conn.Sections {
+		if sec.Journey == nil {
+			continue
+		}
+		dep, arr := sec.Departure, sec.Arrival
+		if dep.Prognosis == nil || dep.Prognosis.Departure == "" ||
+			arr.Prognosis == nil || arr.Prognosis.Arrival == "" {
+			continue
+		}
+
+		line := normalizeLine(sec.Journey.Category + sec.Journey.Number)
+		_, err := r.db.Exec(insertSQL,
+			line, StationKey(dep.Station), StationKey(arr.Station),
+			dep.Departure, dep.Prognosis.Departure,
+			arr.Arrival, arr.Prognosis.Arrival,
+			now.Format(time.RFC3339),
+		)
+		if err != nil {
+			return fmt.Errorf("recording observation: %w", err)
+		}
+	}
+	return r.purge(now)
+}
+
+// purge deletes observations older than rollingWindow.
+func (r *Recorder) purge(now time.Time) error {
+	cutoff := now.Add(-rollingWindow).Format(time.RFC3339)
+	if _, err := r.db.Exec(`DELETE FROM observations WHERE observed_at < ?`, cutoff); err != nil {
+		return fmt.Errorf("purging old observations: %w", err)
+	}
+	return nil
+}
+
+// StationKey returns the identifier an observation is keyed by: the
+// backend's canonical ID when it has one, falling back to the station name
+// (mirrors the asymmetry already documented on providers.Station.ID).
+// Callers resolving a user-typed station (e.g. via stations.ResolveStation)
+// must run the result through StationKey before querying, so a fuzzy match
+// like "zrh hb" keys against the same value Observe recorded.
+func StationKey(s providers.Station) string {
+	if s.ID != "" {
+		return s.ID
+	}
+	return s.Name
+}
+
+// normalizeLine collapses a "<category> <number>" pair into the compact,
+// case-insensitive form users type (and the one shown in reports), e.g.
+// "S", "14" -> "S14". Observe and Query both normalize through this so
+// lookups aren't sensitive to spacing or case.
+func normalizeLine(line string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(line), ""))
+}
+
+// Report is a journey-time prediction for one line between two stations, at
+// the hour of day and weekday the report was requested.
+type Report struct {
+	Line             string
+	From, To         string
+	ScheduledMinutes float64
+	ObservedMinutes  float64
+	RMSEMinutes      float64
+	N                int
+}
+
+// Query predicts the journey time for line from "from" to "to", bucketed to
+// the current hour of day and weekday, from r's observation history. from
+// and to must be the same identifiers (station name or ID) the observations
+// were recorded under (see StationKey); line is normalized the same way
+// Observe stores it, so "s 14" and "S14" both match.
+//
+// Prediction is a simple recurrence: the bucket's mean observed duration,
+// plus the RMSE of observed vs scheduled duration across that bucket's
+// observations, so callers can show an uncertainty band alongside the
+// estimate. Returns an error if the bucket has no observations yet.
+func Query(r *Recorder, line, from, to string) (Report, error) {
+	line = normalizeLine(line)
+	rows, err := r.db.Query(
+		`SELECT scheduled_dep, actual_dep, scheduled_arr, actual_arr FROM observations WHERE line = ? AND from_id = ? AND to_id = ?`,
+		line, from, to,
+	)
+	if err != nil {
+		return Report{}, fmt.Errorf("querying observations: %w", err)
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	wantHour, wantWeekday := now.Hour(), now.Weekday()
+
+	var scheduledSecs, actualSecs []float64
+	for rows.Next() {
+		var schedDep, actDep, schedArr, actArr string
+		if err := rows.Scan(&schedDep, &actDep, &schedArr, &actArr); err != nil {
+			return Report{}, fmt.Errorf("reading observation: %w", err)
+		}
+
+		schedDepT, err1 := parseTime(schedDep)
+		actDepT, err2 := parseTime(actDep)
+		schedArrT, err3 := parseTime(schedArr)
+		actArrT, err4 := parseTime(actArr)
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+			continue
+		}
+
+		// Bucket using scheduled_dep's own offset, not the machine's local
+		// zone: a train's Zurich rush-hour bucket must stay correct even
+		// when this query runs from a CLI in another timezone.
+		if schedDepT.Hour() != wantHour || schedDepT.Weekday() != wantWeekday {
+			continue
+		}
+
+		scheduledSecs = append(scheduledSecs, schedArrT.Sub(schedDepT).Seconds())
+		actualSecs = append(actualSecs, actArrT.Sub(actDepT).Seconds())
+	}
+	if err := rows.Err(); err != nil {
+		return Report{}, fmt.Errorf("reading observations: %w", err)
+	}
+
+	n := len(actualSecs)
+	if n == 0 {
+		return Report{}, fmt.Errorf("no observations for %s %s→%s at this hour/weekday yet", line, from, to)
+	}
+
+	var schedSum, actualSum float64
+	for i := range actualSecs {
+		schedSum += scheduledSecs[i]
+		actualSum += actualSecs[i]
+	}
+	schedMean := schedSum / float64(n)
+	actualMean := actualSum / float64(n)
+
+	var sqErrSum float64
+	for i := range actualSecs {
+		d := actualSecs[i] - scheduledSecs[i]
+		sqErrSum += d * d
+	}
+	rmse := math.Sqrt(sqErrSum / float64(n))
+
+	return Report{
+		Line:             line,
+		From:             from,
+		To:               to,
+		ScheduledMinutes: schedMean / 60,
+		ObservedMinutes:  actualMean / 60,
+		RMSEMinutes:      rmse / 60,
+		N:                n,
+	}, nil
+}
+
+// parseTime parses an opendata.ch/Entur ISO8601-ish timestamp, trying the
+// RFC3339 layout first and falling back to the colon-less offset variant
+// opendata.ch returns (e.g. "2016-01-01T18:40:00+0100"). Mirrors
+// render.parseTime, since observations are recorded straight from whichever
+// provider answered the query.
+func parseTime(t string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, t); err == nil {
+		return parsed, nil
+	}
+	return time.Parse("2006-01-02T15:04:05-0700", t)
+}
@@ -0,0 +1,227 @@
+package stats
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mikeshootzz/SBB-CLI/providers"
+)
+
+func newTestRecorder(t *testing.T) *Recorder {
+	t.Helper()
+	r, err := NewRecorder(filepath.Join(t.TempDir(), "stats.db"))
+	if err != nil {
+		t.Fatalf("NewRecorder returned error: %v", err)
+	}
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+// observationAt builds a one-section Connection whose scheduled departure
+// falls at "at" (so Query's hour/weekday bucketing matches it), scheduled
+// duration schedMin, and an actual duration schedMin+delayMin late.
+func observationAt(at time.Time, schedMin, delayMin int) providers.Connection {
+	schedDep := at
+	schedArr := at.Add(time.Duration(schedMin) * time.Minute)
+	actDep := at
+	actArr := at.Add(time.Duration(schedMin+delayMin) * time.Minute)
+
+	return providers.Connection{
+		Sections: []providers.Section{
+			{
+				Journey: &providers.Journey{Category: "S", Number: "14"},
+				Departure: providers.Stop{
+					Station:   providers.Station{Name: "Zurich HB"},
+					Departure: schedDep.Format(time.RFC3339),
+					Prognosis: &providers.Prognosis{Departure: actDep.Format(time.RFC3339)},
+				},
+				Arrival: providers.Stop{
+					Station:   providers.Station{Name: "Winterthur"},
+					Arrival:   schedArr.Format(time.RFC3339),
+					Prognosis: &providers.Prognosis{Arrival: actArr.Format(time.RFC3339)},
+				},
+			},
+		},
+	}
+}
+
+func TestObserveSkipsLegsWithoutPrognosis(t *testing.T) {
+	r := newTestRecorder(t)
+	conn := providers.Connection{
+		Sections: []providers.Section{
+			{
+				Journey:   &providers.Journey{Category: "S", Number: "14"},
+				Departure: providers.Stop{Station: providers.Station{Name: "Zurich HB"}, Departure: "2026-01-01T08:00:00+01:00"},
+				Arrival:   providers.Stop{Station: providers.Station{Name: "Winterthur"}, Arrival: "2026-01-01T08:23:00+01:00"},
+			},
+		},
+	}
+	if err := r.Observe(conn); err != nil {
+		t.Fatalf("Observe returned error: %v", err)
+	}
+	if _, err := Query(r, "S14", "Zurich HB", "Winterthur"); err == nil {
+		t.Fatalf("expected no observations to have been recorded")
+	}
+}
+
+func TestObserveSkipsWalkingTransfers(t *testing.T) {
+	r := newTestRecorder(t)
+	conn := providers.Connection{
+		Sections: []providers.Section{
+			{
+				Journey: nil,
+				Departure: providers.Stop{
+					Station:   providers.Station{Name: "Zurich HB"},
+					Departure: "2026-01-01T08:00:00+01:00",
+					Prognosis: &providers.Prognosis{Departure: "2026-01-01T08:00:00+01:00"},
+				},
+				Arrival: providers.Stop{
+					Station:   providers.Station{Name: "Winterthur"},
+					Arrival:   "2026-01-01T08:23:00+01:00",
+					Prognosis: &providers.Prognosis{Arrival: "2026-01-01T08:23:00+01:00"},
+				},
+			},
+		},
+	}
+	if err := r.Observe(conn); err != nil {
+		t.Fatalf("Observe returned error: %v", err)
+	}
+	if _, err := Query(r, "S14", "Zurich HB", "Winterthur"); err == nil {
+		t.Fatalf("expected walking transfers not to be recorded")
+	}
+}
+
+func TestObserveAndQueryComputesMeanAndRMSE(t *testing.T) {
+	r := newTestRecorder(t)
+	now := time.Now()
+
+	// Two observations: one exactly on schedule, one two minutes late.
+	if err := r.Observe(observationAt(now, 23, 0)); err != nil {
+		t.Fatalf("Observe returned error: %v", err)
+	}
+	if err := r.Observe(observationAt(now, 23, 2)); err != nil {
+		t.Fatalf("Observe returned error: %v", err)
+	}
+
+	report, err := Query(r, "s 14", "Zurich HB", "Winterthur")
+	if err != nil {
+		t.Fatalf("Query returned error: %v", err)
+	}
+	if report.N != 2 {
+		t.Errorf("N = %d, want 2", report.N)
+	}
+	if report.Line != "S14" {
+		t.Errorf("Line = %q, want S14 (normalized, spaceless)", report.Line)
+	}
+	if got, want := report.ScheduledMinutes, 23.0; got != want {
+		t.Errorf("ScheduledMinutes = %v, want %v", got, want)
+	}
+	if got, want := report.ObservedMinutes, 24.0; got != want {
+		t.Errorf("ObservedMinutes = %v, want %v", got, want)
+	}
+	// Deviations from schedule are 0min and 2min, so RMSE = sqrt((0^2+2^2)/2).
+	if got, want := report.RMSEMinutes, math.Sqrt(2); got != want {
+		t.Errorf("RMSEMinutes = %v, want %v", got, want)
+	}
+}
+
+func TestQueryOnlyMatchesSameHourAndWeekdayBucket(t *testing.T) {
+	r := newTestRecorder(t)
+	now := time.Now()
+	other := now.Add(3 * 24 * time.Hour) // a different weekday and/or hour bucket
+
+	if err := r.Observe(observationAt(other, 23, 5)); err != nil {
+		t.Fatalf("Observe returned error: %v", err)
+	}
+
+	if _, err := Query(r, "S14", "Zurich HB", "Winterthur"); err == nil {
+		t.Fatalf("expected an observation in a different hour/weekday bucket not to match")
+	}
+}
+
+func TestQueryParsesColonLessOffset(t *testing.T) {
+	r := newTestRecorder(t)
+	now := time.Now()
+	schedDep := now
+	schedArr := now.Add(23 * time.Minute)
+	actArr := now.Add(24 * time.Minute)
+
+	colonLess := func(t time.Time) string { return t.Format("2006-01-02T15:04:05-0700") }
+
+	_, err := r.db.Exec(insertSQL,
+		"S14", "Zurich HB", "Winterthur",
+		colonLess(schedDep), colonLess(schedDep),
+		colonLess(schedArr), colonLess(actArr),
+		time.Now().Format(time.RFC3339),
+	)
+	if err != nil {
+		t.Fatalf("seeding observation failed: %v", err)
+	}
+
+	report, err := Query(r, "S14", "Zurich HB", "Winterthur")
+	if err != nil {
+		t.Fatalf("Query returned error for colon-less offsets: %v", err)
+	}
+	if report.N != 1 {
+		t.Errorf("N = %d, want 1", report.N)
+	}
+}
+
+func TestPurgeRemovesObservationsOlderThanRollingWindow(t *testing.T) {
+	r := newTestRecorder(t)
+	now := time.Now()
+
+	old := now.Add(-(rollingWindow + 24*time.Hour))
+	_, err := r.db.Exec(insertSQL,
+		"S14", "Zurich HB", "Winterthur",
+		now.Format(time.RFC3339), now.Format(time.RFC3339),
+		now.Add(23*time.Minute).Format(time.RFC3339), now.Add(23*time.Minute).Format(time.RFC3339),
+		old.Format(time.RFC3339),
+	)
+	if err != nil {
+		t.Fatalf("seeding stale observation failed: %v", err)
+	}
+
+	if err := r.purge(now); err != nil {
+		t.Fatalf("purge returned error: %v", err)
+	}
+
+	if _, err := Query(r, "S14", "Zurich HB", "Winterthur"); err == nil {
+		t.Fatalf("expected the stale observation to have been purged")
+	}
+}
+
+func TestStationKeyPrefersID(t *testing.T) {
+	if got, want := StationKey(providers.Station{Name: "Oslo S", ID: "NSR:StopPlace:1"}), "NSR:StopPlace:1"; got != want {
+		t.Errorf("StationKey = %q, want %q", got, want)
+	}
+	if got, want := StationKey(providers.Station{Name: "Zurich HB"}), "Zurich HB"; got != want {
+		t.Errorf("StationKey = %q, want %q", got, want)
+	}
+}
+
+func TestNormalizeLine(t *testing.T) {
+	cases := map[string]string{
+		"S 14":  "S14",
+		"s14":   "S14",
+		" S 14": "S14",
+	}
+	for in, want := range cases {
+		if got := normalizeLine(in); got != want {
+			t.Errorf("normalizeLine(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseTimeFallsBackToColonLessOffset(t *testing.T) {
+	got, err := parseTime("2016-01-01T18:40:00+0100")
+	if err != nil {
+		t.Fatalf("parseTime returned error: %v", err)
+	}
+	want := time.Date(2016, 1, 1, 18, 40, 0, 0, time.FixedZone("", 3600))
+	if !got.Equal(want) {
+		t.Errorf("parseTime = %v, want %v", got, want)
+	}
+}